@@ -1,10 +1,16 @@
 package hasura
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/hasura/go-graphql-client"
+	"github.com/zoobr/csxlib/csxerrors"
 )
 
 const (
@@ -27,3 +33,84 @@ func GetGplClient(mode int, addr, token string) *graphql.Client {
 	}
 	return client
 }
+
+// error kinds for Hasura & GraphQL client error codes
+var errorKinds = map[string]csxerrors.ErrorKind{
+	"not-found":              csxerrors.NotFound,
+	"constraint-violation":   csxerrors.Conflict,
+	"permission-denied":      csxerrors.PermissionDenied,
+	"access-denied":          csxerrors.PermissionDenied,
+	"permission-error":       csxerrors.PermissionDenied,
+	"invalid-jwt":            csxerrors.PermissionDenied,
+	"jwt-invalid-claims":     csxerrors.PermissionDenied,
+	"invalid-headers":        csxerrors.PermissionDenied,
+	"validation-failed":      csxerrors.Invalid,
+	"parse-failed":           csxerrors.Invalid,
+	"bad-request":            csxerrors.Invalid,
+	"invalid-params":         csxerrors.Invalid,
+	"data-exception":         csxerrors.Invalid,
+	"unexpected":             csxerrors.Internal,
+	graphql.ErrGraphQLEncode: csxerrors.Invalid,
+}
+
+// regexp for HTTP status of non-200 response in request error message of gpl client
+var statusRe = regexp.MustCompile(`^(\d{3}) [^;]*; body: `)
+
+// ClassifyError wraps error returned by gpl client with csxerrors kind.
+// ctx is the context of the call: gpl client loses wrapped errors, so timeout & cancellation
+// are detected by ctx. Cancelled call is Internal, not Unavailable. Otherwise kind is taken from the code of the first GraphQL error. Classified errors are returned as is
+func ClassifyError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.As(err, new(*csxerrors.Error)) {
+		return err
+	}
+	if ctx != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return csxerrors.Wrap(csxerrors.Timeout, err, "hasura")
+	}
+	return csxerrors.Wrap(errorKind(ctx, err), err, "hasura")
+}
+
+// errorKind returns kind of gpl client error
+func errorKind(ctx context.Context, err error) csxerrors.ErrorKind {
+	var gqlErrs graphql.Errors
+	if !errors.As(err, &gqlErrs) || len(gqlErrs) == 0 {
+		return csxerrors.Kind(err)
+	}
+	code, _ := gqlErrs[0].Extensions["code"].(string)
+	if code == graphql.ErrRequestError {
+		return requestErrorKind(ctx, gqlErrs[0].Message)
+	}
+	if kind, ok := errorKinds[code]; ok {
+		return kind
+	}
+	return csxerrors.Internal
+}
+
+// requestErrorKind returns kind of request error by its message.
+// It is either non-200 HTTP status or transport failure
+func requestErrorKind(ctx context.Context, msg string) csxerrors.ErrorKind {
+	if strings.HasPrefix(msg, "problem constructing request") {
+		return csxerrors.Invalid
+	}
+	m := statusRe.FindStringSubmatch(msg)
+	if m == nil {
+		if ctx != nil && ctx.Err() != nil {
+			return csxerrors.Internal // call was cancelled by caller, Hasura isn't down
+		}
+		return csxerrors.Unavailable // transport failure
+	}
+	status, _ := strconv.Atoi(m[1])
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return csxerrors.PermissionDenied
+	case status == http.StatusRequestTimeout:
+		return csxerrors.Timeout
+	case status == http.StatusTooManyRequests || status >= 500:
+		return csxerrors.Unavailable
+	case status >= 400:
+		return csxerrors.Invalid
+	}
+	return csxerrors.Internal
+}
@@ -0,0 +1,151 @@
+package hasura
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hasura/go-graphql-client"
+	"github.com/zoobr/csxlib/clients/hasuratest"
+	"github.com/zoobr/csxlib/csxerrors"
+)
+
+type userQuery struct {
+	User struct {
+		ID graphql.Int
+	} `graphql:"user(id: 1)"`
+}
+
+// queryUser executes GetUser query & returns classified error
+func queryUser(ctx context.Context, url string) error {
+	client := GetGplClient(AuthModeSecret, url, "secret")
+	err := client.Query(ctx, &userQuery{}, nil, graphql.OperationName("GetUser"))
+	return ClassifyError(ctx, err)
+}
+
+func TestClassifyErrorCodes(t *testing.T) {
+	codes := map[string]csxerrors.ErrorKind{
+		"not-found":            csxerrors.NotFound,
+		"constraint-violation": csxerrors.Conflict,
+		"permission-denied":    csxerrors.PermissionDenied,
+		"access-denied":        csxerrors.PermissionDenied,
+		"permission-error":     csxerrors.PermissionDenied,
+		"invalid-jwt":          csxerrors.PermissionDenied,
+		"jwt-invalid-claims":   csxerrors.PermissionDenied,
+		"invalid-headers":      csxerrors.PermissionDenied,
+		"validation-failed":    csxerrors.Invalid,
+		"parse-failed":         csxerrors.Invalid,
+		"bad-request":          csxerrors.Invalid,
+		"invalid-params":       csxerrors.Invalid,
+		"data-exception":       csxerrors.Invalid,
+		"unexpected":           csxerrors.Internal,
+		"some-new-code":        csxerrors.Internal,
+	}
+	for code, kind := range codes {
+		t.Run(code, func(t *testing.T) {
+			srv := hasuratest.NewMockServer(t)
+			srv.ExpectQuery("GetUser").RespondError(code, "failed")
+
+			err := queryUser(context.Background(), srv.URL())
+			if k := csxerrors.Kind(err); k != kind {
+				t.Errorf("expected %s, got %s (%v)", kind, k, err)
+			}
+			var gqlErrs graphql.Errors
+			if !errors.As(err, &gqlErrs) {
+				t.Error("graphql errors are lost")
+			}
+		})
+	}
+}
+
+func TestClassifyErrorHTTPStatus(t *testing.T) {
+	statuses := map[int]csxerrors.ErrorKind{
+		http.StatusBadRequest:          csxerrors.Invalid,
+		http.StatusNotFound:            csxerrors.Invalid,
+		http.StatusUnauthorized:        csxerrors.PermissionDenied,
+		http.StatusForbidden:           csxerrors.PermissionDenied,
+		http.StatusRequestTimeout:      csxerrors.Timeout,
+		http.StatusTooManyRequests:     csxerrors.Unavailable,
+		http.StatusInternalServerError: csxerrors.Unavailable,
+		http.StatusBadGateway:          csxerrors.Unavailable,
+		http.StatusServiceUnavailable:  csxerrors.Unavailable,
+	}
+	for status, kind := range statuses {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "failed", status)
+		}))
+		err := queryUser(context.Background(), srv.URL)
+		srv.Close()
+
+		if k := csxerrors.Kind(err); k != kind {
+			t.Errorf("%d: expected %s, got %s (%v)", status, kind, k, err)
+		}
+	}
+}
+
+func TestClassifyErrorTransport(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	err := queryUser(context.Background(), url)
+	if k := csxerrors.Kind(err); k != csxerrors.Unavailable {
+		t.Errorf("expected unavailable, got %s (%v)", k, err)
+	}
+}
+
+func TestClassifyErrorTimeout(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer srv.Close()
+	defer close(done) // release handler before server is closed
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := queryUser(ctx, srv.URL)
+	if k := csxerrors.Kind(err); k != csxerrors.Timeout {
+		t.Errorf("expected timeout, got %s (%v)", k, err)
+	}
+}
+
+func TestClassifyErrorCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-done
+	}))
+	defer srv.Close()
+	defer close(done) // release handler before server is closed
+
+	err := queryUser(ctx, srv.URL)
+	if k := csxerrors.Kind(err); k != csxerrors.Internal {
+		t.Errorf("expected internal, got %s (%v)", k, err)
+	}
+}
+
+func TestClassifyErrorClassified(t *testing.T) {
+	if err := ClassifyError(context.Background(), nil); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+
+	err := csxerrors.Wrap(csxerrors.Internal, errors.New("x"), "hasura")
+	if res := ClassifyError(context.Background(), err); res != err {
+		t.Errorf("classified error was wrapped again: %v", res)
+	}
+
+	res := ClassifyError(context.Background(), errors.New("x"))
+	res = ClassifyError(context.Background(), res)
+	if strings.Count(res.Error(), "hasura") != 1 || csxerrors.Kind(res) != csxerrors.Internal {
+		t.Errorf("unexpected error %v", res)
+	}
+}
@@ -0,0 +1,153 @@
+package hasuratest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+const (
+	OperationQuery    = "query"
+	OperationMutation = "mutation"
+)
+
+// Expectation is an expected GraphQL operation & the response for it.
+// It may be changed while the server handles requests
+type Expectation struct {
+	mu        *sync.Mutex     // mutex of the server which owns the expectation
+	opType    string          // operation type (query/mutation)
+	name      string          // operation name
+	variables interface{}     // expected variables (normalized)
+	matchVars bool            // whether variables must be matched
+	response  json.RawMessage // response body which is sent back to the client
+	times     int             // how many times operation is expected. 0 means any positive number
+	calls     int             // how many times operation was called
+}
+
+// WithVariables sets variables which the operation must be called with
+func (e *Expectation) WithVariables(variables map[string]interface{}) *Expectation {
+	vars := normalizeJSON(variables)
+	e.mu.Lock()
+	e.variables, e.matchVars = vars, true
+	e.mu.Unlock()
+	return e
+}
+
+// Times sets how many times the operation is expected to be called
+func (e *Expectation) Times(n int) *Expectation {
+	e.mu.Lock()
+	e.times = n
+	e.mu.Unlock()
+	return e
+}
+
+// Respond sets data which is sent back as "data" field of the response.
+// data may be any JSON-marshallable value or raw JSON as json.RawMessage, []byte or string
+func (e *Expectation) Respond(data interface{}) *Expectation {
+	raw, err := marshalRaw(data)
+	if err == nil && !json.Valid(raw) {
+		err = fmt.Errorf("invalid JSON %q", raw)
+	}
+	var resp []byte
+	if err == nil {
+		resp, err = json.Marshal(map[string]json.RawMessage{"data": raw})
+	}
+	if err != nil {
+		panic(fmt.Sprintf("hasuratest: can't marshal response for %s: %v", e.name, err))
+	}
+	e.setResponse(resp)
+	return e
+}
+
+// RespondError sets Hasura error envelope which is sent back to the client
+func (e *Expectation) RespondError(code, message string) *Expectation {
+	e.setResponse(errorEnvelope(code, message))
+	return e
+}
+
+// setResponse sets response body under the server lock
+func (e *Expectation) setResponse(resp json.RawMessage) {
+	e.mu.Lock()
+	e.response = resp
+	e.mu.Unlock()
+}
+
+// matches checks if request matches the expectation. Must be called under the server lock
+func (e *Expectation) matches(req *gqlRequest) bool {
+	if e.opType != req.opType || e.name != req.name {
+		return false
+	}
+	if e.times > 0 && e.calls >= e.times {
+		return false
+	}
+	return !e.matchVars || reflect.DeepEqual(e.variables, normalizeJSON(req.Variables))
+}
+
+// isMet checks if the expectation was called expected number of times
+func (e *Expectation) isMet() bool {
+	if e.times > 0 {
+		return e.calls == e.times
+	}
+	return e.calls > 0
+}
+
+// String returns human-readable description of the expectation
+func (e *Expectation) String() string {
+	s := fmt.Sprintf("%s %s", e.opType, e.name)
+	if e.matchVars {
+		vars, _ := json.Marshal(e.variables)
+		s += " with variables " + string(vars)
+	}
+	return s
+}
+
+// marshalRaw marshals value to JSON. json.RawMessage, []byte & string are used as is
+func marshalRaw(v interface{}) (json.RawMessage, error) {
+	switch val := v.(type) {
+	case json.RawMessage:
+		return val, nil
+	case []byte:
+		return json.RawMessage(val), nil
+	case string:
+		return json.RawMessage(val), nil
+	}
+	return json.Marshal(v)
+}
+
+// normalizeJSON converts value to the form of the same value decoded from JSON,
+// so values built in Go code & values received via HTTP can be compared
+func normalizeJSON(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	raw, err := marshalRaw(v)
+	if err != nil {
+		return v
+	}
+	if len(raw) == 0 {
+		return nil
+	}
+	var res interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber() // keep big integers exact
+	if err := dec.Decode(&res); err != nil {
+		return v
+	}
+	if m, ok := res.(map[string]interface{}); ok && len(m) == 0 {
+		return nil // empty variables are the same as omitted ones
+	}
+	return res
+}
+
+// errorEnvelope returns Hasura error response body
+func errorEnvelope(code, message string) json.RawMessage {
+	body, _ := json.Marshal(map[string]interface{}{
+		"errors": []map[string]interface{}{{
+			"message":    message,
+			"extensions": map[string]interface{}{"path": "$", "code": code},
+		}},
+	})
+	return body
+}
@@ -0,0 +1,121 @@
+package hasuratest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+)
+
+// fixtureEntry is a recorded GraphQL operation & its response
+type fixtureEntry struct {
+	Type          string          `json:"type"`
+	OperationName string          `json:"operationName"`
+	Variables     json.RawMessage `json:"variables,omitempty"`
+	Response      json.RawMessage `json:"response"`
+
+	used bool // whether entry was already replayed
+}
+
+// fixture is a golden file with recorded responses
+type fixture struct {
+	path     string // golden file path
+	upstream string // real Hasura URL used in record mode
+
+	mu      sync.Mutex
+	entries []*fixtureEntry
+}
+
+// load reads recorded entries from golden file
+func (f *fixture) load() error {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &f.entries)
+}
+
+// save writes recorded entries to golden file
+func (f *fixture) save() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, err := json.MarshalIndent(f.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(f.path, append(data, '\n'), 0o644)
+}
+
+// record sends request to upstream & saves the response
+func (f *fixture) record(header http.Header, req *gqlRequest) (json.RawMessage, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, f.upstream, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header = header.Clone()
+	httpReq.Header.Del("Content-Length")
+	httpReq.Header.Del("Accept-Encoding") // response is stored as is, so it must not be compressed
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("upstream responded %s: %s", resp.Status, respBody)
+	}
+
+	respBody = bytes.TrimSpace(respBody)
+	f.mu.Lock()
+	f.entries = append(f.entries, &fixtureEntry{
+		Type:          req.opType,
+		OperationName: req.name,
+		Variables:     req.Variables,
+		Response:      respBody,
+	})
+	f.mu.Unlock()
+
+	return respBody, nil
+}
+
+// replay returns recorded response for request. Entries are replayed in recorded order,
+// so the same operation called several times gets its responses in the same order
+func (f *fixture) replay(req *gqlRequest) (json.RawMessage, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	vars := normalizeJSON(req.Variables)
+	var last *fixtureEntry
+	for _, e := range f.entries {
+		if e.Type != req.opType || e.OperationName != req.name || !reflect.DeepEqual(normalizeJSON(e.Variables), vars) {
+			continue
+		}
+		if !e.used {
+			e.used = true
+			return e.Response, true
+		}
+		last = e
+	}
+	if last != nil {
+		return last.Response, true // all matching entries are used, repeat the last one
+	}
+	return nil, false
+}
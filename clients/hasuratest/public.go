@@ -0,0 +1,54 @@
+// Package hasuratest provides mock Hasura GraphQL server for tests.
+//
+// Mock server registers expected operations by name:
+//
+//	srv := hasuratest.NewMockServer(t)
+//	srv.ExpectQuery("GetUser").WithVariables(map[string]interface{}{"id": 1}).Respond(data)
+//	client := hasura.GetGplClient(hasura.AuthModeSecret, srv.URL(), "secret")
+//
+// Fixture server records real responses to golden file & replays them later:
+//
+//	srv := hasuratest.NewFixtureServer(t, "testdata/users.json", "http://localhost:8080/v1/graphql")
+//
+// Operations are matched by type (query/mutation), name & variables. Name is taken from
+// operationName field of the request or from the GraphQL document itself.
+package hasuratest
+
+import (
+	"os"
+)
+
+// RecordEnv is an environment variable which switches fixture server to record mode
+const RecordEnv = "HASURATEST_RECORD"
+
+// NewMockServer starts mock server which responds using registered expectations.
+// Unmet expectations are reported when the test finishes
+func NewMockServer(t TestingT) *MockServer {
+	return newServer(t, modeMock, nil)
+}
+
+// NewRecordServer starts server which proxies requests to upstream Hasura
+// & writes responses to golden file when the test finishes successfully
+func NewRecordServer(t TestingT, goldenPath, upstream string) *MockServer {
+	return newServer(t, modeRecord, &fixture{path: goldenPath, upstream: upstream})
+}
+
+// NewReplayServer starts server which responds using responses from golden file
+func NewReplayServer(t TestingT, goldenPath string) *MockServer {
+	t.Helper()
+	fx := &fixture{path: goldenPath}
+	if err := fx.load(); err != nil {
+		t.Fatalf("hasuratest: can't read golden file %s: %v", goldenPath, err)
+	}
+	return newServer(t, modeReplay, fx)
+}
+
+// NewFixtureServer starts record server if RecordEnv environment variable is set,
+// otherwise replay server
+func NewFixtureServer(t TestingT, goldenPath, upstream string) *MockServer {
+	t.Helper()
+	if os.Getenv(RecordEnv) != "" {
+		return NewRecordServer(t, goldenPath, upstream)
+	}
+	return NewReplayServer(t, goldenPath)
+}
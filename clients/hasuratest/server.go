@@ -0,0 +1,212 @@
+package hasuratest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+)
+
+const (
+	modeMock   = iota // responses are taken from registered expectations
+	modeRecord        // requests are proxied to upstream & responses are saved to golden file
+	modeReplay        // responses are taken from golden file
+)
+
+// TestingT is a part of testing.TB used by mock server
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+	Cleanup(func())
+	Failed() bool
+}
+
+// gqlRequest is a GraphQL-over-HTTP request
+type gqlRequest struct {
+	Query         string          `json:"query"`
+	Variables     json.RawMessage `json:"variables,omitempty"` // kept raw, so numbers aren't changed when recorded
+	OperationName string          `json:"operationName,omitempty"`
+
+	opType string // operation type parsed from query
+	name   string // operation name parsed from query or taken from OperationName
+}
+
+// regexp for operation type & name at the beginning of GraphQL document
+var operationRe = regexp.MustCompile(`^\s*(query|mutation|subscription)\b\s*([_A-Za-z][_0-9A-Za-z]*)?`)
+
+// parse sets operation type & name of the request
+func (r *gqlRequest) parse() {
+	r.opType = OperationQuery // shorthand "{ ... }" document is a query
+	if m := operationRe.FindStringSubmatch(r.Query); m != nil {
+		r.opType, r.name = m[1], m[2]
+	}
+	if r.OperationName != "" {
+		r.name = r.OperationName
+	}
+}
+
+// MockServer is a Hasura GraphQL server for tests. The real GraphQL client may be used against it
+type MockServer struct {
+	t      TestingT
+	server *httptest.Server
+	mode   int
+
+	mu           sync.Mutex
+	expectations []*Expectation
+	verified     bool
+	closed       bool
+
+	fixture *fixture // golden file data for record & replay modes
+}
+
+// newServer creates & starts server in given mode. Server is closed automatically on test cleanup
+func newServer(t TestingT, mode int, fx *fixture) *MockServer {
+	s := &MockServer{t: t, mode: mode, fixture: fx}
+	s.server = httptest.NewServer(s)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// URL returns GraphQL endpoint URL of the server
+func (s *MockServer) URL() string {
+	return s.server.URL
+}
+
+// ExpectQuery registers expected query with given operation name
+func (s *MockServer) ExpectQuery(name string) *Expectation {
+	return s.expect(OperationQuery, name)
+}
+
+// ExpectMutation registers expected mutation with given operation name
+func (s *MockServer) ExpectMutation(name string) *Expectation {
+	return s.expect(OperationMutation, name)
+}
+
+// expect registers expected operation
+func (s *MockServer) expect(opType, name string) *Expectation {
+	e := &Expectation{mu: &s.mu, opType: opType, name: name, response: []byte(`{"data":null}`)}
+	s.mu.Lock()
+	s.expectations = append(s.expectations, e)
+	s.mu.Unlock()
+	return e
+}
+
+// Verify reports all expectations which weren't met
+func (s *MockServer) Verify() {
+	s.t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.verified = true
+	for _, e := range s.expectations {
+		if !e.isMet() {
+			if e.times > 0 {
+				s.t.Errorf("hasuratest: %s expected %d call(s), got %d", e, e.times, e.calls)
+			} else {
+				s.t.Errorf("hasuratest: %s was not called", e)
+			}
+		}
+	}
+}
+
+// Close shuts down the server. Unmet expectations are reported if Verify wasn't called,
+// recorded responses are written to golden file in record mode unless the test failed
+func (s *MockServer) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	verified := s.verified
+	s.mu.Unlock()
+
+	s.server.Close()
+	switch s.mode {
+	case modeMock:
+		if !verified {
+			s.Verify()
+		}
+	case modeRecord:
+		if s.t.Failed() {
+			return // don't overwrite good golden file with results of failed run
+		}
+		if err := s.fixture.save(); err != nil {
+			s.t.Errorf("hasuratest: can't write golden file %s: %v", s.fixture.path, err)
+		}
+	}
+}
+
+// ServeHTTP handles single & batch GraphQL-over-HTTP requests
+func (s *MockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST method is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var resp []byte
+	if body = bytes.TrimSpace(body); len(body) > 0 && body[0] == '[' {
+		var reqs []*gqlRequest
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			resp = errorEnvelope("parse-failed", err.Error())
+		} else {
+			batch := make([]json.RawMessage, len(reqs))
+			for i, req := range reqs {
+				batch[i] = s.handle(r.Header, req)
+			}
+			resp, _ = json.Marshal(batch)
+		}
+	} else {
+		req := &gqlRequest{}
+		if err := json.Unmarshal(body, req); err != nil {
+			resp = errorEnvelope("parse-failed", err.Error())
+		} else {
+			resp = s.handle(r.Header, req)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(resp)
+}
+
+// handle returns response body for single GraphQL request
+func (s *MockServer) handle(header http.Header, req *gqlRequest) json.RawMessage {
+	req.parse()
+
+	switch s.mode {
+	case modeRecord:
+		resp, err := s.fixture.record(header, req)
+		if err != nil {
+			s.t.Errorf("hasuratest: can't record %s %s: %v", req.opType, req.name, err)
+			return errorEnvelope("unexpected", err.Error())
+		}
+		return resp
+	case modeReplay:
+		if resp, ok := s.fixture.replay(req); ok {
+			return resp
+		}
+	default:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, e := range s.expectations {
+			if e.matches(req) {
+				e.calls++
+				return e.response
+			}
+		}
+	}
+
+	msg := fmt.Sprintf("unexpected %s %s with variables %s", req.opType, req.name, req.Variables)
+	s.t.Errorf("hasuratest: %s", msg)
+	return errorEnvelope("unexpected", msg)
+}
@@ -0,0 +1,279 @@
+package hasuratest_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hasura/go-graphql-client"
+	hasura "github.com/zoobr/csxlib/clients"
+	"github.com/zoobr/csxlib/clients/hasuratest"
+)
+
+type bigint int64
+
+type getUserQuery struct {
+	User struct {
+		ID   bigint
+		Name string
+	} `graphql:"user(id: $id)"`
+}
+
+// getUser executes GetUser query using real Hasura client
+func getUser(url string, id bigint) (*getUserQuery, error) {
+	client := hasura.GetGplClient(hasura.AuthModeSecret, url, "secret")
+	q := &getUserQuery{}
+	err := client.Query(context.Background(), q, map[string]interface{}{"id": id}, graphql.OperationName("GetUser"))
+	return q, err
+}
+
+// fakeT records errors instead of failing the test
+type fakeT struct {
+	errors   []string
+	cleanups []func()
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Fatalf(format string, args ...interface{}) {
+	t.Errorf(format, args...)
+}
+
+func (t *fakeT) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+func (t *fakeT) Failed() bool {
+	return len(t.errors) > 0
+}
+
+func TestNamedQueryWithVariables(t *testing.T) {
+	srv := hasuratest.NewMockServer(t)
+	srv.ExpectQuery("GetUser").
+		WithVariables(map[string]interface{}{"id": 1}).
+		Respond(map[string]interface{}{"user": map[string]interface{}{"id": 1, "name": "bob"}})
+
+	q, err := getUser(srv.URL(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.User.ID != 1 || q.User.Name != "bob" {
+		t.Errorf("unexpected response %+v", q.User)
+	}
+}
+
+func TestRespondError(t *testing.T) {
+	srv := hasuratest.NewMockServer(t)
+	srv.ExpectQuery("GetUser").RespondError("permission-error", "field not found")
+
+	_, err := getUser(srv.URL(), 1)
+	errs, ok := err.(graphql.Errors)
+	if !ok || len(errs) != 1 {
+		t.Fatalf("expected graphql.Errors, got %v", err)
+	}
+	if errs[0].Message != "field not found" || errs[0].Extensions["code"] != "permission-error" {
+		t.Errorf("unexpected error %+v", errs[0])
+	}
+}
+
+func TestTimesOrdering(t *testing.T) {
+	srv := hasuratest.NewMockServer(t)
+	srv.ExpectQuery("GetUser").Times(1).Respond(`{"user":{"id":1,"name":"first"}}`)
+	srv.ExpectQuery("GetUser").Times(2).Respond(`{"user":{"id":1,"name":"next"}}`)
+
+	for _, want := range []string{"first", "next", "next"} {
+		q, err := getUser(srv.URL(), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.User.Name != want {
+			t.Errorf("expected %s, got %s", want, q.User.Name)
+		}
+	}
+}
+
+func TestUnmetExpectations(t *testing.T) {
+	ft := &fakeT{}
+	srv := hasuratest.NewMockServer(ft)
+	srv.ExpectQuery("GetUser").Times(1)
+	srv.ExpectMutation("DeleteUser").Times(2)
+	srv.ExpectMutation("UpdateUser").Times(1)
+
+	client := hasura.GetGplClient(hasura.AuthModeSecret, srv.URL(), "secret")
+	var m struct {
+		UpdateUser struct{ ID bigint } `graphql:"update_user"`
+	}
+	if err := client.Mutate(context.Background(), &m, nil, graphql.OperationName("UpdateUser")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := getUser(srv.URL(), 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := getUser(srv.URL(), 2); err == nil {
+		t.Error("expected error for call above Times limit")
+	}
+
+	for _, f := range ft.cleanups {
+		f()
+	}
+	if len(ft.errors) != 2 {
+		t.Fatalf("expected 2 errors, got %q", ft.errors)
+	}
+	if !strings.Contains(ft.errors[0], "unexpected query GetUser") {
+		t.Errorf("unexpected error %q", ft.errors[0])
+	}
+	if !strings.Contains(ft.errors[1], "mutation DeleteUser expected 2 call(s), got 0") {
+		t.Errorf("unexpected error %q", ft.errors[1])
+	}
+}
+
+func TestBatchRequest(t *testing.T) {
+	srv := hasuratest.NewMockServer(t)
+	srv.ExpectQuery("A").Respond(`{"a":1}`)
+	srv.ExpectMutation("B").WithVariables(map[string]interface{}{"x": "y"}).RespondError("constraint-violation", "dup")
+
+	body := `[
+		{"query": "query A { a }"},
+		{"query": "mutation B($x: String!) { b(x: $x) }", "variables": {"x": "y"}}
+	]`
+	resp, err := http.Post(srv.URL(), "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var res []struct {
+		Data   json.RawMessage
+		Errors []struct {
+			Message    string
+			Extensions map[string]interface{}
+		}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 {
+		t.Fatalf("expected 2 responses, got %d", len(res))
+	}
+	if string(res[0].Data) != `{"a":1}` {
+		t.Errorf("unexpected data %s", res[0].Data)
+	}
+	if len(res[1].Errors) != 1 || res[1].Errors[0].Extensions["code"] != "constraint-violation" {
+		t.Errorf("unexpected errors %+v", res[1].Errors)
+	}
+}
+
+func TestRecordReplay(t *testing.T) {
+	const id = bigint(9007199254740993) // not representable as float64
+
+	var upstreamBody string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Hasura-Admin-Secret") != "secret" {
+			t.Errorf("auth header wasn't forwarded")
+		}
+		body, _ := io.ReadAll(r.Body)
+		upstreamBody = string(body)
+		fmt.Fprintf(w, `{"data":{"user":{"id":%d,"name":"recorded"}}}`, id)
+	}))
+	defer upstream.Close()
+
+	golden := filepath.Join(t.TempDir(), "users.json")
+	rec := hasuratest.NewRecordServer(t, golden, upstream.URL)
+	if _, err := getUser(rec.URL(), id); err != nil {
+		t.Fatal(err)
+	}
+	rec.Close()
+
+	if !strings.Contains(upstreamBody, `"id":9007199254740993`) {
+		t.Errorf("variables were changed when forwarded: %s", upstreamBody)
+	}
+	data, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"id": 9007199254740993`) {
+		t.Errorf("variables were changed in golden file: %s", data)
+	}
+
+	rp := hasuratest.NewReplayServer(t, golden)
+	q, err := getUser(rp.URL(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.User.ID != id || q.User.Name != "recorded" {
+		t.Errorf("unexpected response %+v", q.User)
+	}
+}
+
+func TestRecordFailedTestKeepsGoldenFile(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusBadGateway)
+	}))
+	defer upstream.Close()
+
+	golden := filepath.Join(t.TempDir(), "users.json")
+	if err := os.WriteFile(golden, []byte("[]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ft := &fakeT{}
+	rec := hasuratest.NewRecordServer(ft, golden, upstream.URL)
+	if _, err := getUser(rec.URL(), 1); err == nil {
+		t.Error("expected error when upstream is down")
+	}
+	rec.Close()
+
+	if !ft.Failed() {
+		t.Error("expected recording error to be reported")
+	}
+	if data, _ := os.ReadFile(golden); string(data) != "[]\n" {
+		t.Errorf("golden file was overwritten: %s", data)
+	}
+}
+
+func TestRespondInvalidJSONPanics(t *testing.T) {
+	ft := &fakeT{}
+	srv := hasuratest.NewMockServer(ft)
+	defer func() {
+		for _, f := range ft.cleanups {
+			f()
+		}
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "GetUser") {
+			t.Errorf("expected panic mentioning operation, got %v", r)
+		}
+	}()
+	srv.ExpectQuery("GetUser").Respond("hello")
+}
+
+func TestExpectationChangedDuringRequests(t *testing.T) {
+	srv := hasuratest.NewMockServer(t)
+	e := srv.ExpectQuery("GetUser").Respond(`{"user":{"id":1,"name":"bob"}}`)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			if _, err := getUser(srv.URL(), 1); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		e.WithVariables(map[string]interface{}{"id": 1}).
+			Times(0).
+			Respond(`{"user":{"id":1,"name":"bob"}}`)
+	}
+	<-done
+}
@@ -0,0 +1,95 @@
+// Package csxerrors defines error kinds shared by all csxlib packages,
+// so the whole stack agrees on what an error means
+package csxerrors
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrorKind is a kind of error. Use Kind or Is to get kind of error
+type ErrorKind int
+
+const (
+	None             ErrorKind = iota // kind of nil error
+	Internal                          // unexpected error. Default kind for errors without kind
+	NotFound                          // requested entity doesn't exist
+	Conflict                          // entity already exists or was changed concurrently
+	PermissionDenied                  // caller isn't allowed to do the operation
+	Unavailable                       // dependency (database, Hasura, etc.) is unavailable
+	Timeout                           // operation wasn't completed in time
+	Invalid                           // request is invalid
+)
+
+// kind names used in logs & metrics labels
+var kindNames = map[ErrorKind]string{
+	None:             "",
+	Internal:         "internal",
+	NotFound:         "not_found",
+	Conflict:         "conflict",
+	PermissionDenied: "permission_denied",
+	Unavailable:      "unavailable",
+	Timeout:          "timeout",
+	Invalid:          "invalid",
+}
+
+// String returns kind name
+func (k ErrorKind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return kindNames[Internal]
+}
+
+// Error is an error with kind
+type Error struct {
+	Kind ErrorKind // kind of error
+	Msg  string    // message describing the error
+	Err  error     // wrapped error
+}
+
+// Error implements error interface
+func (e *Error) Error() string {
+	switch {
+	case e.Err == nil:
+		return e.Msg
+	case e.Msg == "":
+		return e.Err.Error()
+	}
+	return e.Msg + ": " + e.Err.Error()
+}
+
+// Unwrap returns wrapped error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap wraps err with kind & message. If err is nil new error with message is created.
+// None kind is replaced with Internal, because error always has a kind
+func Wrap(kind ErrorKind, err error, msg string) error {
+	if kind == None {
+		kind = Internal
+	}
+	return &Error{Kind: kind, Msg: msg, Err: err}
+}
+
+// Kind returns kind of error. Kind of the outermost *Error in the chain wins.
+// Errors without kind are Internal except context deadline which is Timeout
+func Kind(err error) ErrorKind {
+	if err == nil {
+		return None
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Timeout
+	}
+	return Internal
+}
+
+// Is reports whether error has given kind. It is a shortcut for Kind(err) == kind
+func Is(err error, kind ErrorKind) bool {
+	return Kind(err) == kind
+}
@@ -0,0 +1,97 @@
+package csxerrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestWrap(t *testing.T) {
+	base := errors.New("no rows")
+	err := fmt.Errorf("get user: %w", Wrap(NotFound, base, "user 1"))
+
+	if err.Error() != "get user: user 1: no rows" {
+		t.Errorf("unexpected message %q", err)
+	}
+	if !errors.Is(err, base) {
+		t.Error("wrapped error is lost")
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.Kind != NotFound {
+		t.Errorf("expected *Error with NotFound kind, got %v", err)
+	}
+	if msg := Wrap(Invalid, nil, "bad id").Error(); msg != "bad id" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	if msg := Wrap(Invalid, base, "").Error(); msg != "no rows" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	if kind := Kind(Wrap(None, base, "")); kind != Internal {
+		t.Errorf("expected Internal for None kind, got %s", kind)
+	}
+}
+
+func TestKind(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		kind ErrorKind
+	}{
+		{"nil", nil, None},
+		{"plain", errors.New("x"), Internal},
+		{"wrapped", Wrap(Conflict, errors.New("x"), "dup"), Conflict},
+		{"outermost wins", Wrap(Conflict, Wrap(NotFound, nil, "x"), "y"), Conflict},
+		{"inside fmt wrap", fmt.Errorf("a: %w", Wrap(PermissionDenied, nil, "x")), PermissionDenied},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), Timeout},
+		{"explicit kind over deadline", Wrap(Unavailable, context.DeadlineExceeded, "x"), Unavailable},
+		{"canceled", context.Canceled, Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if kind := Kind(tt.err); kind != tt.kind {
+				t.Errorf("expected %s, got %s", tt.kind, kind)
+			}
+			for kind := None; kind <= Invalid; kind++ {
+				if Is(tt.err, kind) != (kind == tt.kind) {
+					t.Errorf("Is(%s) disagrees with Kind", kind)
+				}
+			}
+		})
+	}
+}
+
+func TestKindString(t *testing.T) {
+	if s := None.String(); s != "" {
+		t.Errorf("expected empty name for None, got %q", s)
+	}
+	if s := PermissionDenied.String(); s != "permission_denied" {
+		t.Errorf("unexpected name %q", s)
+	}
+	if s := ErrorKind(100).String(); s != "internal" {
+		t.Errorf("expected internal for unknown kind, got %q", s)
+	}
+}
+
+func TestKindToHTTPStatus(t *testing.T) {
+	statuses := map[ErrorKind]int{
+		None:             http.StatusOK,
+		Internal:         http.StatusInternalServerError,
+		NotFound:         http.StatusNotFound,
+		Conflict:         http.StatusConflict,
+		PermissionDenied: http.StatusForbidden,
+		Unavailable:      http.StatusServiceUnavailable,
+		Timeout:          http.StatusGatewayTimeout,
+		Invalid:          http.StatusBadRequest,
+		ErrorKind(100):   http.StatusInternalServerError,
+	}
+	for kind, status := range statuses {
+		if s := KindToHTTPStatus(kind); s != status {
+			t.Errorf("%s: expected %d, got %d", kind, status, s)
+		}
+	}
+	if s := HTTPStatus(Wrap(NotFound, nil, "x")); s != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", s)
+	}
+}
@@ -0,0 +1,28 @@
+package csxerrors
+
+import "net/http"
+
+// HTTP statuses for error kinds
+var httpStatuses = map[ErrorKind]int{
+	None:             http.StatusOK,
+	Internal:         http.StatusInternalServerError,
+	NotFound:         http.StatusNotFound,
+	Conflict:         http.StatusConflict,
+	PermissionDenied: http.StatusForbidden,
+	Unavailable:      http.StatusServiceUnavailable,
+	Timeout:          http.StatusGatewayTimeout,
+	Invalid:          http.StatusBadRequest,
+}
+
+// KindToHTTPStatus returns HTTP status code for error kind
+func KindToHTTPStatus(kind ErrorKind) int {
+	if status, ok := httpStatuses[kind]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// HTTPStatus returns HTTP status code for error
+func HTTPStatus(err error) int {
+	return KindToHTTPStatus(Kind(err))
+}
@@ -6,6 +6,7 @@ import (
 	kitmetrics "github.com/go-kit/kit/metrics"
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/zoobr/csxlib/csxerrors"
 )
 
 // prometheusMetrics is a struct for Prometheus metrics
@@ -14,8 +15,9 @@ type prometheusMetrics struct {
 	reqDurationMetric kitmetrics.Histogram // requests duration metric
 }
 
-// common labels for metrics: method - method name, res - result of method execution (success/error)
-var labelNames = []string{"method", "res"}
+// common labels for metrics: method - method name, res - result of method execution (success/error),
+// kind - csxerrors kind of error (empty on success)
+var labelNames = []string{"method", "res", "kind"}
 
 // getMetricLabelValues returns array of label names & values for metrics
 func getMetricLabelValues(methodName string, err error) []string {
@@ -23,7 +25,7 @@ func getMetricLabelValues(methodName string, err error) []string {
 	if err != nil {
 		res = "error"
 	}
-	return []string{"method", methodName, "res", res}
+	return []string{"method", methodName, "res", res, "kind", csxerrors.Kind(err).String()}
 }
 
 // init initializes Prometheus metrics using namespace & subsystem
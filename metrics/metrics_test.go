@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zoobr/csxlib/csxerrors"
+)
+
+func TestGetMetricLabelValues(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		res  string
+		kind string
+	}{
+		{"success", nil, "success", ""},
+		{"plain error", errors.New("x"), "error", "internal"},
+		{"wrapped error", csxerrors.Wrap(csxerrors.NotFound, errors.New("x"), "user"), "error", "not_found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lvs := getMetricLabelValues("users.Get", tt.err)
+			expected := []string{"method", "users.Get", "res", tt.res, "kind", tt.kind}
+			if len(lvs) != len(expected) {
+				t.Fatalf("expected %q, got %q", expected, lvs)
+			}
+			for i := range expected {
+				if lvs[i] != expected[i] {
+					t.Errorf("expected %q, got %q", expected, lvs)
+					break
+				}
+			}
+			for i := 0; i < len(lvs); i += 2 {
+				if lvs[i] != labelNames[i/2] {
+					t.Errorf("label %q doesn't match labelNames %q", lvs[i], labelNames)
+				}
+			}
+		})
+	}
+}
+
+func TestCollectWithKindLabel(t *testing.T) {
+	InitNop()
+	Collect("users.Get", func() error { return nil })
+	Collect("users.Get", func() error { return csxerrors.Wrap(csxerrors.Conflict, nil, "dup") })
+}